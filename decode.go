@@ -3,8 +3,11 @@ package bencode
 import (
 	"bufio"
 	"bytes"
+	"encoding"
 	"errors"
+	"fmt"
 	"io"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -12,7 +15,22 @@ import (
 
 // A Decoder reads and decodes bencoded values from an input stream.
 type Decoder struct {
-	b *bufio.Reader
+	b      *countingReader
+	stack  []byte
+	strict bool
+}
+
+// Strict enables or disables strict mode, which rejects bencode that, while
+// parseable, violates the well-formedness rules of BEP 3: dictionary keys
+// must appear in sorted order as raw byte strings, integers must not have
+// leading zeros (except "0" itself) or a "-0", and string lengths must not
+// have leading zeros. Violations are reported as a *SyntaxError pointing at
+// the offending byte. Strict mode is off by default.
+//
+// Decode and Skip check the order of dictionary keys; Token checks the
+// integer and string-length rules but not the order of dictionary keys.
+func (dec *Decoder) Strict(strict bool) {
+	dec.strict = strict
 }
 
 // NewDecoder returns a new decoder that reads from r.
@@ -20,16 +38,96 @@ type Decoder struct {
 // The decoder introduces its own buffering and may read data from r beyond the
 // bencoded values requested.
 func NewDecoder(r io.Reader) *Decoder {
-	b := bufio.NewReader(r)
-	return &Decoder{b}
+	b := &countingReader{b: bufio.NewReader(r)}
+	return &Decoder{b: b}
 }
 
-func (dec *Decoder) decodeInteger(delim byte) (int, error) {
+// countingReader wraps a *bufio.Reader, tracking the number of bytes
+// consumed from it and, when tee is non-nil, copying every consumed byte
+// into tee as well. This lets a Decoder recover the exact raw bytes of a
+// value it has just parsed.
+type countingReader struct {
+	b   *bufio.Reader
+	off int64
+	tee *bytes.Buffer
+}
+
+func (cr *countingReader) consume(p []byte) {
+	cr.off += int64(len(p))
+	if cr.tee != nil {
+		cr.tee.Write(p)
+	}
+}
+
+func (cr *countingReader) ReadByte() (byte, error) {
+	c, err := cr.b.ReadByte()
+	if err != nil {
+		return c, err
+	}
+	cr.consume([]byte{c})
+	return c, nil
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.b.Read(p)
+	cr.consume(p[:n])
+	return n, err
+}
+
+func (cr *countingReader) ReadBytes(delim byte) ([]byte, error) {
+	s, err := cr.b.ReadBytes(delim)
+	cr.consume(s)
+	return s, err
+}
+
+func (cr *countingReader) Peek(n int) ([]byte, error) {
+	return cr.b.Peek(n)
+}
+
+// Discard skips n bytes. When a tee is in progress, the skipped bytes are
+// routed through Read so they still get captured; otherwise it delegates to
+// the underlying bufio.Reader's allocation-free Discard.
+func (cr *countingReader) Discard(n int) (int, error) {
+	if cr.tee == nil {
+		discarded, err := cr.b.Discard(n)
+		cr.off += int64(discarded)
+		return discarded, err
+	}
+	buf := make([]byte, n)
+	read := 0
+	for read < n {
+		m, err := cr.Read(buf[read:])
+		read += m
+		if err != nil {
+			return read, err
+		}
+	}
+	return read, nil
+}
+
+// decodeIntegerDigits reads the raw digits of a bencoded integer up to
+// (and discarding) delim, without interpreting them. This lets callers that
+// need more range or precision than an int (such as big.Int) parse the
+// digits themselves.
+func (dec *Decoder) decodeIntegerDigits(delim byte) ([]byte, error) {
 	s, err := dec.b.ReadBytes(delim)
+	if err != nil {
+		return nil, err
+	}
+	return s[:len(s)-1], nil
+}
+
+func (dec *Decoder) decodeInteger(delim byte) (int, error) {
+	start := dec.b.off
+	s, err := dec.decodeIntegerDigits(delim)
 	if err != nil {
 		return 0, err
 	}
-	s = s[:len(s)-1]
+	if dec.strict {
+		if verr := validateIntegerDigits(s); verr != nil {
+			return 0, &SyntaxError{start, verr.Error()}
+		}
+	}
 	n, err := strconv.Atoi(string(s))
 	if err != nil {
 		return 0, err
@@ -38,7 +136,17 @@ func (dec *Decoder) decodeInteger(delim byte) (int, error) {
 }
 
 func (dec *Decoder) decodeString() ([]byte, error) {
-	length, err := dec.decodeInteger(':')
+	start := dec.b.off
+	digits, err := dec.decodeIntegerDigits(':')
+	if err != nil {
+		return nil, err
+	}
+	if dec.strict {
+		if verr := validateLengthDigits(digits); verr != nil {
+			return nil, &SyntaxError{start, verr.Error()}
+		}
+	}
+	length, err := strconv.Atoi(string(digits))
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +162,28 @@ func (dec *Decoder) decodeString() ([]byte, error) {
 	return s, nil
 }
 
+func (dec *Decoder) decodeBigInt(n *big.Int) error {
+	if ok, err := dec.next('i'); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("cannot unmarshal into *big.Int")
+	}
+	start := dec.b.off
+	digits, err := dec.decodeIntegerDigits('e')
+	if err != nil {
+		return err
+	}
+	if dec.strict {
+		if verr := validateIntegerDigits(digits); verr != nil {
+			return &SyntaxError{start, verr.Error()}
+		}
+	}
+	if _, ok := n.SetString(string(digits), 10); !ok {
+		return fmt.Errorf("bencode: invalid integer %q", digits)
+	}
+	return nil
+}
+
 func (dec *Decoder) next(c byte) (bool, error) {
 	buf, err := dec.b.Peek(1)
 	if err != nil {
@@ -78,6 +208,30 @@ func (dec *Decoder) Decode(v interface{}) error {
 	if val.Kind() != reflect.Ptr {
 		return errors.New("non-pointer type")
 	}
+	if n, ok := v.(*big.Int); ok {
+		return dec.decodeBigInt(n)
+	}
+	if u, ok := v.(Unmarshaler); ok {
+		b, err := dec.captureValue()
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalBencode(b)
+	}
+	if u, ok := v.(encoding.BinaryUnmarshaler); ok {
+		s, err := dec.decodeString()
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalBinary(s)
+	}
+	if u, ok := v.(encoding.TextUnmarshaler); ok {
+		s, err := dec.decodeString()
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalText(s)
+	}
 	val = val.Elem()
 	typ := val.Type()
 	kind := typ.Kind()
@@ -94,17 +248,77 @@ func (dec *Decoder) Decode(v interface{}) error {
 			return err
 		}
 		val.Set(reflect.ValueOf(string(s)))
-	case kind == reflect.Int:
+	case kind == reflect.Int, kind == reflect.Int8, kind == reflect.Int16,
+		kind == reflect.Int32, kind == reflect.Int64:
 		if ok, err := dec.next('i'); err != nil {
 			return err
 		} else if !ok {
-			return errors.New("cannot unmarshal into Go value of type int")
+			return fmt.Errorf("cannot unmarshal into Go value of type %s", typ)
+		}
+		start := dec.b.off
+		digits, err := dec.decodeIntegerDigits('e')
+		if err != nil {
+			return err
+		}
+		if dec.strict {
+			if verr := validateIntegerDigits(digits); verr != nil {
+				return &SyntaxError{start, verr.Error()}
+			}
+		}
+		n, err := strconv.ParseInt(string(digits), 10, 64)
+		if err != nil {
+			return err
+		}
+		if val.OverflowInt(n) {
+			return &OverflowError{string(digits), typ}
+		}
+		val.SetInt(n)
+	case kind == reflect.Uint, kind == reflect.Uint8, kind == reflect.Uint16,
+		kind == reflect.Uint32, kind == reflect.Uint64:
+		if ok, err := dec.next('i'); err != nil {
+			return err
+		} else if !ok {
+			return fmt.Errorf("cannot unmarshal into Go value of type %s", typ)
+		}
+		start := dec.b.off
+		digits, err := dec.decodeIntegerDigits('e')
+		if err != nil {
+			return err
+		}
+		if dec.strict {
+			if verr := validateIntegerDigits(digits); verr != nil {
+				return &SyntaxError{start, verr.Error()}
+			}
+		}
+		n, err := strconv.ParseUint(string(digits), 10, 64)
+		if err != nil {
+			return err
+		}
+		if val.OverflowUint(n) {
+			return &OverflowError{string(digits), typ}
+		}
+		val.SetUint(n)
+	case kind == reflect.Bool:
+		if ok, err := dec.next('i'); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("cannot unmarshal into Go value of type bool")
 		}
 		n, err := dec.decodeInteger('e')
 		if err != nil {
 			return err
 		}
-		val.Set(reflect.ValueOf(n))
+		if n != 0 && n != 1 {
+			return fmt.Errorf("bencode: invalid bool value %d", n)
+		}
+		val.SetBool(n != 0)
+	case kind == reflect.Ptr:
+		if val.IsNil() {
+			val.Set(reflect.New(typ.Elem()))
+		}
+		if err := dec.Decode(val.Interface()); err != nil {
+			return err
+		}
 	case kind == reflect.Slice:
 		if ok, err := dec.next('l'); err != nil {
 			return err
@@ -148,16 +362,24 @@ func (dec *Decoder) Decode(v interface{}) error {
 			}
 			fields[name] = val.Field(i)
 		}
+		var prevKey []byte
 		for {
 			if done, err := dec.next('e'); err != nil {
 				return err
 			} else if done {
 				break
 			}
+			keyStart := dec.b.off
 			key, err := dec.decodeString()
 			if err != nil {
 				return err
 			}
+			if dec.strict {
+				if prevKey != nil && bytes.Compare(key, prevKey) <= 0 {
+					return &SyntaxError{keyStart, "dictionary keys not in sorted order"}
+				}
+				prevKey = key
+			}
 			field, ok := fields[string(key)]
 			if !ok {
 				if err := dec.discard(); err != nil {
@@ -169,12 +391,61 @@ func (dec *Decoder) Decode(v interface{}) error {
 				return err
 			}
 		}
+	case kind == reflect.Map:
+		ktyp := typ.Key()
+		if ktyp.Kind() != reflect.String {
+			return errors.New("unsupported map key type")
+		}
+		if ok, err := dec.next('d'); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("cannot unmarshal into Go map")
+		}
+		if val.IsNil() {
+			val.Set(reflect.MakeMap(typ))
+		}
+		etyp := typ.Elem()
+		var prevKey []byte
+		for {
+			if done, err := dec.next('e'); err != nil {
+				return err
+			} else if done {
+				break
+			}
+			keyStart := dec.b.off
+			key, err := dec.decodeString()
+			if err != nil {
+				return err
+			}
+			if dec.strict {
+				if prevKey != nil && bytes.Compare(key, prevKey) <= 0 {
+					return &SyntaxError{keyStart, "dictionary keys not in sorted order"}
+				}
+				prevKey = key
+			}
+			elem := reflect.New(etyp)
+			if err := dec.Decode(elem.Interface()); err != nil {
+				return err
+			}
+			val.SetMapIndex(reflect.ValueOf(string(key)).Convert(ktyp), elem.Elem())
+		}
 	default:
 		return errors.New("unsupported type")
 	}
 	return nil
 }
 
+// captureValue decodes and discards the next bencoded value, returning the
+// exact raw bytes it consumed.
+func (dec *Decoder) captureValue() ([]byte, error) {
+	dec.b.tee = new(bytes.Buffer)
+	defer func() { dec.b.tee = nil }()
+	if err := dec.discard(); err != nil {
+		return nil, err
+	}
+	return dec.b.tee.Bytes(), nil
+}
+
 func (dec *Decoder) discard() error {
 	buf, err := dec.b.Peek(1)
 	if err != nil {
@@ -183,12 +454,17 @@ func (dec *Decoder) discard() error {
 	c := buf[0]
 	switch {
 	case c >= '0' && c <= '9':
-		s, err := dec.b.ReadBytes(':')
+		start := dec.b.off
+		digits, err := dec.decodeIntegerDigits(':')
 		if err != nil {
 			return err
 		}
-		s = s[:len(s)-1]
-		n, err := strconv.Atoi(string(s))
+		if dec.strict {
+			if verr := validateLengthDigits(digits); verr != nil {
+				return &SyntaxError{start, verr.Error()}
+			}
+		}
+		n, err := strconv.Atoi(string(digits))
 		if err != nil {
 			return err
 		}
@@ -196,9 +472,19 @@ func (dec *Decoder) discard() error {
 			return err
 		}
 	case c == 'i':
-		if _, err := dec.b.ReadBytes('e'); err != nil {
+		if _, err := dec.b.ReadByte(); err != nil {
 			return err
 		}
+		start := dec.b.off
+		digits, err := dec.decodeIntegerDigits('e')
+		if err != nil {
+			return err
+		}
+		if dec.strict {
+			if verr := validateIntegerDigits(digits); verr != nil {
+				return &SyntaxError{start, verr.Error()}
+			}
+		}
 	case c == 'l':
 		if _, err := dec.b.ReadByte(); err != nil {
 			return err
@@ -217,15 +503,24 @@ func (dec *Decoder) discard() error {
 		if _, err := dec.b.ReadByte(); err != nil {
 			return err
 		}
+		var prevKey []byte
 		for {
 			if done, err := dec.next('e'); err != nil {
 				return err
 			} else if done {
 				break
 			}
-			if err := dec.discard(); err != nil {
+			keyStart := dec.b.off
+			key, err := dec.decodeString()
+			if err != nil {
 				return err
 			}
+			if dec.strict {
+				if prevKey != nil && bytes.Compare(key, prevKey) <= 0 {
+					return &SyntaxError{keyStart, "dictionary keys not in sorted order"}
+				}
+				prevKey = key
+			}
 			if err := dec.discard(); err != nil {
 				return err
 			}
@@ -2,6 +2,8 @@ package bencode
 
 import (
 	"bytes"
+	"fmt"
+	"math/big"
 	"strings"
 	"testing"
 )
@@ -117,3 +119,143 @@ func TestDecode_struct(t *testing.T) {
 		t.Error("wrong value(s) in struct")
 	}
 }
+
+func TestDecode_map(t *testing.T) {
+	r := strings.NewReader("d4:buzzi5e4:fizzi3ee")
+	var v map[string]int
+	if err := NewDecoder(r).Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if len(v) != 2 {
+		t.Fatal("wrong number of entries in map")
+	} else if v["fizz"] != 3 || v["buzz"] != 5 {
+		t.Error("wrong value(s) in map")
+	}
+}
+
+func TestDecode_map_namedStringKey(t *testing.T) {
+	type key string
+	r := strings.NewReader("d4:buzzi5ee")
+	var v map[key]int
+	if err := NewDecoder(r).Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if v["buzz"] != 5 {
+		t.Error("wrong value in map")
+	}
+}
+
+func TestDecode_int64(t *testing.T) {
+	r := strings.NewReader("i1099511627776e")
+	var v int64
+	if err := NewDecoder(r).Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if v != 1<<40 {
+		t.Error("decoded wrong value for int64")
+	}
+}
+
+func TestDecode_int8_overflow(t *testing.T) {
+	r := strings.NewReader("i200e")
+	var v int8
+	err := NewDecoder(r).Decode(&v)
+	if _, ok := err.(*OverflowError); !ok {
+		t.Fatalf("expected *OverflowError, got %v", err)
+	}
+}
+
+func TestDecode_bool(t *testing.T) {
+	r := strings.NewReader("i1e")
+	var v bool
+	if err := NewDecoder(r).Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if !v {
+		t.Error("decoded wrong value for bool")
+	}
+}
+
+func TestDecode_bool_invalid(t *testing.T) {
+	r := strings.NewReader("i5e")
+	var v bool
+	if err := NewDecoder(r).Decode(&v); err == nil {
+		t.Fatal("expected error decoding invalid bool value")
+	}
+}
+
+func TestDecode_pointer(t *testing.T) {
+	r := strings.NewReader("i5e")
+	var v *int
+	if err := NewDecoder(r).Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if v == nil || *v != 5 {
+		t.Error("decoded wrong value for pointer")
+	}
+}
+
+func TestDecode_bigInt(t *testing.T) {
+	r := strings.NewReader("i123456789012345678901234567890e")
+	n := new(big.Int)
+	if err := NewDecoder(r).Decode(n); err != nil {
+		t.Fatal(err)
+	} else if n.String() != "123456789012345678901234567890" {
+		t.Error("decoded wrong value for *big.Int")
+	}
+}
+
+func TestDecode_rawMessage(t *testing.T) {
+	r := strings.NewReader("d4:infod6:lengthi1024eee")
+	var v struct {
+		Info RawMessage `bencode:"info"`
+	}
+	if err := NewDecoder(r).Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if string(v.Info) != "d6:lengthi1024ee" {
+		t.Error("decoded wrong raw bytes for RawMessage")
+	}
+}
+
+func TestDecoder_Token(t *testing.T) {
+	r := strings.NewReader("d4:listli1ei2eee")
+	dec := NewDecoder(r)
+	var got []Token
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, tok)
+		if _, ok := tok.(DictEnd); ok {
+			break
+		}
+	}
+	want := []Token{
+		DictStart{}, String("list"), ListStart{}, Int(1), Int(2), ListEnd{}, DictEnd{},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d tokens, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if fmt.Sprintf("%#v", got[i]) != fmt.Sprintf("%#v", want[i]) {
+			t.Errorf("token %d: got %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDecoder_Token_more_and_skip(t *testing.T) {
+	r := strings.NewReader("l1:a1:b2:cce")
+	dec := NewDecoder(r)
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for dec.More() {
+		if err := dec.Skip(); err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("skipped %d elements, want 3", n)
+	}
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+}
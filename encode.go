@@ -3,8 +3,10 @@ package bencode
 import (
 	"bufio"
 	"bytes"
+	"encoding"
 	"errors"
 	"io"
+	"math/big"
 	"reflect"
 	"sort"
 	"strconv"
@@ -36,6 +38,60 @@ func (enc *Encoder) encodeString(v []byte) error {
 	return nil
 }
 
+// encodeMarshaler checks whether v, or a pointer to v, implements Marshaler,
+// and otherwise whether v itself implements encoding.BinaryMarshaler or
+// encoding.TextMarshaler, and if so writes its encoding to the stream. The
+// bool result reports whether v was handled.
+func (enc *Encoder) encodeMarshaler(v interface{}) (bool, error) {
+	if m, ok := v.(Marshaler); ok {
+		return true, enc.writeMarshaled(m)
+	}
+	if val := reflect.ValueOf(v); val.IsValid() && val.Kind() != reflect.Ptr {
+		pv := reflect.New(val.Type())
+		pv.Elem().Set(val)
+		if m, ok := pv.Interface().(Marshaler); ok {
+			return true, enc.writeMarshaled(m)
+		}
+	}
+	if m, ok := v.(encoding.BinaryMarshaler); ok {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			return true, err
+		}
+		return true, enc.encodeString(b)
+	}
+	if m, ok := v.(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return true, err
+		}
+		return true, enc.encodeString(b)
+	}
+	return false, nil
+}
+
+func (enc *Encoder) writeMarshaled(m Marshaler) error {
+	b, err := m.MarshalBencode()
+	if err != nil {
+		return err
+	}
+	if !validBencode(b) {
+		return errors.New("bencode: MarshalBencode returned invalid bencode")
+	}
+	_, err = enc.b.Write(b)
+	return err
+}
+
+func (enc *Encoder) encodeBigInt(n *big.Int) error {
+	if err := enc.b.WriteByte('i'); err != nil {
+		return err
+	}
+	if _, err := enc.b.Write([]byte(n.String())); err != nil {
+		return err
+	}
+	return enc.b.WriteByte('e')
+}
+
 func (enc *Encoder) encode(v interface{}) (err error) {
 	defer func() {
 		if err != nil {
@@ -43,6 +99,15 @@ func (enc *Encoder) encode(v interface{}) (err error) {
 		}
 		err = enc.b.Flush()
 	}()
+	switch n := v.(type) {
+	case *big.Int:
+		return enc.encodeBigInt(n)
+	case big.Int:
+		return enc.encodeBigInt(&n)
+	}
+	if ok, err := enc.encodeMarshaler(v); ok {
+		return err
+	}
 	val := reflect.ValueOf(v)
 	typ := val.Type()
 	kind := typ.Kind()
@@ -56,17 +121,43 @@ func (enc *Encoder) encode(v interface{}) (err error) {
 		if err := enc.encodeString(v.([]byte)); err != nil {
 			return err
 		}
-	case kind == reflect.Int:
+	case kind == reflect.Int, kind == reflect.Int8, kind == reflect.Int16,
+		kind == reflect.Int32, kind == reflect.Int64:
 		if err := enc.b.WriteByte('i'); err != nil {
 			return err
 		}
-		n := strconv.Itoa(v.(int))
+		n := strconv.FormatInt(val.Int(), 10)
 		if _, err := enc.b.Write([]byte(n)); err != nil {
 			return err
 		}
 		if err := enc.b.WriteByte('e'); err != nil {
 			return err
 		}
+	case kind == reflect.Uint, kind == reflect.Uint8, kind == reflect.Uint16,
+		kind == reflect.Uint32, kind == reflect.Uint64:
+		if err := enc.b.WriteByte('i'); err != nil {
+			return err
+		}
+		n := strconv.FormatUint(val.Uint(), 10)
+		if _, err := enc.b.Write([]byte(n)); err != nil {
+			return err
+		}
+		if err := enc.b.WriteByte('e'); err != nil {
+			return err
+		}
+	case kind == reflect.Bool:
+		s := "i0e"
+		if val.Bool() {
+			s = "i1e"
+		}
+		if _, err := enc.b.Write([]byte(s)); err != nil {
+			return err
+		}
+	case kind == reflect.Ptr:
+		if val.IsNil() {
+			return errors.New("cannot encode nil pointer")
+		}
+		return enc.Encode(val.Elem().Interface())
 	case kind == reflect.Slice:
 		if err := enc.b.WriteByte('l'); err != nil {
 			return err
@@ -130,6 +221,35 @@ func (enc *Encoder) encode(v interface{}) (err error) {
 		if err := enc.b.WriteByte('e'); err != nil {
 			return err
 		}
+	case kind == reflect.Map:
+		ktyp := typ.Key()
+		if ktyp.Kind() != reflect.String {
+			return errors.New("unsupported map key type")
+		}
+		if err := enc.b.WriteByte('d'); err != nil {
+			return err
+		}
+		keys := val.MapKeys()
+		names := make([]string, len(keys))
+		byName := make(map[string]reflect.Value, len(keys))
+		for i, k := range keys {
+			name := k.String()
+			names[i] = name
+			byName[name] = k
+		}
+		sort.Sort(sort.StringSlice(names))
+		for _, name := range names {
+			if err := enc.Encode([]byte(name)); err != nil {
+				return err
+			}
+			elem := val.MapIndex(byName[name]).Interface()
+			if err := enc.Encode(elem); err != nil {
+				return err
+			}
+		}
+		if err := enc.b.WriteByte('e'); err != nil {
+			return err
+		}
 	default:
 		return errors.New("unsupported type")
 	}
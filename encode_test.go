@@ -2,6 +2,7 @@ package bencode
 
 import (
 	"bytes"
+	"math/big"
 	"testing"
 )
 
@@ -102,3 +103,79 @@ func TestEncode_struct(t *testing.T) {
 		t.Error("encoded wrong value for struct")
 	}
 }
+
+func TestEncode_map(t *testing.T) {
+	v := map[string]int{"fizz": 3, "buzz": 5}
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(v); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "d4:buzzi5e4:fizzi3ee" {
+		t.Error("encoded wrong value for map")
+	}
+}
+
+func TestEncode_int64(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(int64(1) << 40); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "i1099511627776e" {
+		t.Error("encoded wrong value for int64")
+	}
+}
+
+func TestEncode_uint8(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(uint8(200)); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "i200e" {
+		t.Error("encoded wrong value for uint8")
+	}
+}
+
+func TestEncode_bool(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(true); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "i1e" {
+		t.Error("encoded wrong value for bool")
+	}
+}
+
+func TestEncode_pointer(t *testing.T) {
+	n := 5
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(&n); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "i5e" {
+		t.Error("encoded wrong value for pointer")
+	}
+}
+
+func TestEncode_bigInt(t *testing.T) {
+	n := new(big.Int)
+	n.SetString("123456789012345678901234567890", 10)
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(n); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "i123456789012345678901234567890e" {
+		t.Error("encoded wrong value for *big.Int")
+	}
+}
+
+func TestEncode_rawMessage(t *testing.T) {
+	v := RawMessage("d4:fizzi3ee")
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(v); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "d4:fizzi3ee" {
+		t.Error("encoded wrong value for RawMessage")
+	}
+}
+
+func TestEncode_rawMessage_invalid(t *testing.T) {
+	v := RawMessage("not bencode")
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(v); err == nil {
+		t.Error("expected error for invalid RawMessage")
+	}
+}
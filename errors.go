@@ -0,0 +1,14 @@
+package bencode
+
+import "reflect"
+
+// An OverflowError describes a bencoded integer that does not fit in the
+// destination Go type.
+type OverflowError struct {
+	Value string
+	Type  reflect.Type
+}
+
+func (e *OverflowError) Error() string {
+	return "bencode: integer " + e.Value + " overflows Go type " + e.Type.String()
+}
@@ -0,0 +1,31 @@
+package bencode
+
+import "bytes"
+
+// Marshaler is implemented by types that can encode a bencoding of
+// themselves. MarshalBencode must return valid, complete bencode: a single
+// string, integer, list, or dictionary.
+type Marshaler interface {
+	MarshalBencode() ([]byte, error)
+}
+
+// Unmarshaler is implemented by types that can decode a bencoding of
+// themselves. UnmarshalBencode is passed the raw bytes of exactly one
+// bencoded value, and must copy the data it needs if it wishes to retain it
+// after returning.
+type Unmarshaler interface {
+	UnmarshalBencode([]byte) error
+}
+
+// validBencode reports whether b consists of exactly one well-formed
+// bencoded value with no trailing data.
+func validBencode(b []byte) bool {
+	dec := NewDecoder(bytes.NewReader(b))
+	if err := dec.discard(); err != nil {
+		return false
+	}
+	if _, err := dec.b.ReadByte(); err == nil {
+		return false
+	}
+	return true
+}
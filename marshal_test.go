@@ -0,0 +1,96 @@
+package bencode
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+)
+
+type upperString string
+
+func (s upperString) MarshalBencode() ([]byte, error) {
+	return Marshal(string(s))
+}
+
+func (s *upperString) UnmarshalBencode(b []byte) error {
+	var str string
+	if err := Unmarshal(b, &str); err != nil {
+		return err
+	}
+	*s = upperString(str)
+	return nil
+}
+
+type invalidMarshaler struct{}
+
+func (invalidMarshaler) MarshalBencode() ([]byte, error) {
+	return []byte("not bencode"), nil
+}
+
+type erroringMarshaler struct{}
+
+func (erroringMarshaler) MarshalBencode() ([]byte, error) {
+	return nil, errors.New("boom")
+}
+
+func TestEncode_marshaler_structField(t *testing.T) {
+	v := struct {
+		Name upperString `bencode:"name"`
+	}{Name: "foo"}
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(v); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "d4:name3:fooe" {
+		t.Error("encoded wrong value for struct field implementing Marshaler")
+	}
+}
+
+func TestEncode_marshaler_invalidBencode(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(invalidMarshaler{}); err == nil {
+		t.Error("expected error for MarshalBencode returning invalid bencode")
+	}
+}
+
+func TestEncode_marshaler_error(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(erroringMarshaler{}); err == nil {
+		t.Error("expected error from MarshalBencode")
+	}
+}
+
+func TestDecode_unmarshaler_structField(t *testing.T) {
+	r := bytes.NewReader([]byte("d4:name3:fooe"))
+	var v struct {
+		Name upperString `bencode:"name"`
+	}
+	if err := NewDecoder(r).Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if v.Name != "foo" {
+		t.Error("decoded wrong value for struct field implementing Unmarshaler")
+	}
+}
+
+func TestEncode_netIPTextMarshaler(t *testing.T) {
+	ip := net.ParseIP("192.0.2.1")
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf).Encode(ip); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "9:192.0.2.1" {
+		t.Errorf("encoded %q, want %q", buf.String(), "9:192.0.2.1")
+	}
+}
+
+func TestDecode_netIPTextUnmarshaler(t *testing.T) {
+	b, err := Marshal("192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ip net.IP
+	if err := Unmarshal(b, &ip); err != nil {
+		t.Fatal(err)
+	} else if ip.String() != "192.0.2.1" {
+		t.Errorf("decoded %v, want 192.0.2.1", ip)
+	}
+}
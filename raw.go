@@ -0,0 +1,21 @@
+package bencode
+
+// RawMessage is a raw bencoded value. It implements Marshaler and
+// Unmarshaler so a struct field (or other value) can hold onto the exact
+// input bytes of a value instead of having them decoded and re-encoded,
+// which may not round-trip byte-for-byte (dictionary keys are re-sorted,
+// and integers may be re-canonicalized). The canonical use case is
+// BitTorrent: computing the SHA-1 of an info dictionary exactly as it
+// appeared on disk to derive the infohash.
+type RawMessage []byte
+
+// MarshalBencode returns m unchanged.
+func (m RawMessage) MarshalBencode() ([]byte, error) {
+	return m, nil
+}
+
+// UnmarshalBencode sets *m to a copy of b.
+func (m *RawMessage) UnmarshalBencode(b []byte) error {
+	*m = append((*m)[0:0], b...)
+	return nil
+}
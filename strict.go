@@ -0,0 +1,82 @@
+package bencode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// A SyntaxError reports a bencode value that parses but violates the BEP 3
+// well-formedness rules checked by a Decoder in strict mode (see
+// Decoder.Strict), together with the byte offset at which it was detected.
+type SyntaxError struct {
+	Offset int64
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("bencode: %s (offset %d)", e.Msg, e.Offset)
+}
+
+// validateIntegerDigits checks digits (the contents of an "i...e" token,
+// excluding the 'i' and 'e') against the canonical bencode integer grammar:
+// either a single "0", or an optional leading '-' followed by a nonzero
+// digit and zero or more further digits. "-0" and leading zeros are
+// rejected.
+func validateIntegerDigits(digits []byte) error {
+	s := digits
+	if len(s) == 0 {
+		return errors.New("empty integer")
+	}
+	if s[0] == '-' {
+		s = s[1:]
+		if len(s) == 0 {
+			return errors.New(`integer is a bare "-"`)
+		}
+		if s[0] == '0' {
+			return errors.New("integer has a negative zero or leading zero")
+		}
+	} else if s[0] == '0' && len(s) > 1 {
+		return errors.New("integer has a leading zero")
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("integer contains non-digit character %q", c)
+		}
+	}
+	return nil
+}
+
+// validateLengthDigits checks digits (the contents of a string's length
+// prefix, excluding the ':') against the canonical bencode length grammar:
+// either a single "0" or a nonzero digit and zero or more further digits,
+// with no sign.
+func validateLengthDigits(digits []byte) error {
+	if len(digits) == 0 {
+		return errors.New("empty string length")
+	}
+	if digits[0] == '0' && len(digits) > 1 {
+		return errors.New("string length has a leading zero")
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("string length contains non-digit character %q", c)
+		}
+	}
+	return nil
+}
+
+// UnmarshalStrict is like Unmarshal, but decodes with a Decoder in strict
+// mode (see Decoder.Strict) and additionally rejects any bytes following
+// the decoded value.
+func UnmarshalStrict(data []byte, v interface{}) error {
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.Strict(true)
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if off := dec.b.off; off < int64(len(data)) {
+		return &SyntaxError{off, "trailing data after top-level value"}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package bencode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecoder_Strict_unsortedKeys(t *testing.T) {
+	r := strings.NewReader("d4:fizzi3e4:buzzi5ee")
+	dec := NewDecoder(r)
+	dec.Strict(true)
+	var v struct {
+		Buzz int `bencode:"buzz"`
+		Fizz int `bencode:"fizz"`
+	}
+	err := dec.Decode(&v)
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Fatalf("expected *SyntaxError, got %v", err)
+	}
+}
+
+func TestDecoder_Strict_sortedKeys(t *testing.T) {
+	r := strings.NewReader("d4:buzzi5e4:fizzi3ee")
+	dec := NewDecoder(r)
+	dec.Strict(true)
+	var v struct {
+		Buzz int `bencode:"buzz"`
+		Fizz int `bencode:"fizz"`
+	}
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if v.Buzz != 5 || v.Fizz != 3 {
+		t.Error("wrong value(s) in struct")
+	}
+}
+
+func TestDecoder_Strict_integerLeadingZero(t *testing.T) {
+	r := strings.NewReader("i03e")
+	dec := NewDecoder(r)
+	dec.Strict(true)
+	var v int
+	err := dec.Decode(&v)
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Fatalf("expected *SyntaxError, got %v", err)
+	}
+}
+
+func TestDecoder_Strict_negativeZero(t *testing.T) {
+	r := strings.NewReader("i-0e")
+	dec := NewDecoder(r)
+	dec.Strict(true)
+	var v int
+	err := dec.Decode(&v)
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Fatalf("expected *SyntaxError, got %v", err)
+	}
+}
+
+func TestDecoder_Strict_stringLengthLeadingZero(t *testing.T) {
+	r := strings.NewReader("03:foo")
+	dec := NewDecoder(r)
+	dec.Strict(true)
+	var v string
+	err := dec.Decode(&v)
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Fatalf("expected *SyntaxError, got %v", err)
+	}
+}
+
+func TestDecode_notStrictByDefault(t *testing.T) {
+	r := strings.NewReader("i03e")
+	var v int
+	if err := NewDecoder(r).Decode(&v); err != nil {
+		t.Fatal(err)
+	} else if v != 3 {
+		t.Error("decoded wrong value for integer")
+	}
+}
+
+func TestUnmarshalStrict_trailingData(t *testing.T) {
+	var v int
+	err := UnmarshalStrict([]byte("i1ejunk"), &v)
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Fatalf("expected *SyntaxError, got %v", err)
+	}
+}
+
+func TestUnmarshalStrict_ok(t *testing.T) {
+	var v int
+	if err := UnmarshalStrict([]byte("i1e"), &v); err != nil {
+		t.Fatal(err)
+	} else if v != 1 {
+		t.Error("decoded wrong value for integer")
+	}
+}
@@ -0,0 +1,104 @@
+package bencode
+
+import "errors"
+
+// A Token is one lexical element of a bencoded stream: a DictStart, DictEnd,
+// ListStart, ListEnd, String, or Int.
+type Token interface{}
+
+// DictStart is the Token emitted for the 'd' that begins a dictionary.
+type DictStart struct{}
+
+// DictEnd is the Token emitted for the 'e' that ends a dictionary.
+type DictEnd struct{}
+
+// ListStart is the Token emitted for the 'l' that begins a list.
+type ListStart struct{}
+
+// ListEnd is the Token emitted for the 'e' that ends a list.
+type ListEnd struct{}
+
+// String is the Token emitted for a bencoded string.
+type String []byte
+
+// Int is the Token emitted for a bencoded integer.
+type Int int64
+
+// Token returns the next token in the input stream, without regard to the
+// shape of any Go value. Token allows callers to walk large or unknown
+// bencoded structures (for example, the pieces string or files list of a
+// .torrent file) without decoding the whole value via reflection.
+func (dec *Decoder) Token() (Token, error) {
+	buf, err := dec.b.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	switch c := buf[0]; {
+	case c >= '0' && c <= '9':
+		s, err := dec.decodeString()
+		if err != nil {
+			return nil, err
+		}
+		return String(s), nil
+	case c == 'i':
+		if _, err := dec.b.ReadByte(); err != nil {
+			return nil, err
+		}
+		n, err := dec.decodeInteger('e')
+		if err != nil {
+			return nil, err
+		}
+		return Int(n), nil
+	case c == 'l':
+		if _, err := dec.b.ReadByte(); err != nil {
+			return nil, err
+		}
+		dec.stack = append(dec.stack, 'l')
+		return ListStart{}, nil
+	case c == 'd':
+		if _, err := dec.b.ReadByte(); err != nil {
+			return nil, err
+		}
+		dec.stack = append(dec.stack, 'd')
+		return DictStart{}, nil
+	case c == 'e':
+		if len(dec.stack) == 0 {
+			return nil, errors.New("bencode: unexpected 'e'")
+		}
+		if _, err := dec.b.ReadByte(); err != nil {
+			return nil, err
+		}
+		top := dec.stack[len(dec.stack)-1]
+		dec.stack = dec.stack[:len(dec.stack)-1]
+		if top == 'l' {
+			return ListEnd{}, nil
+		}
+		return DictEnd{}, nil
+	default:
+		return nil, errors.New("bencode: invalid character looking for beginning of value")
+	}
+}
+
+// More reports whether there is another element before the end of the
+// current list or dictionary. It is meant to be used as the condition of a
+// loop following a ListStart or DictStart token.
+func (dec *Decoder) More() bool {
+	buf, err := dec.b.Peek(1)
+	if err != nil {
+		return false
+	}
+	return buf[0] != 'e'
+}
+
+// Skip reads and discards the next complete value, without regard to its
+// shape.
+func (dec *Decoder) Skip() error {
+	return dec.discard()
+}
+
+// InputOffset returns the input stream byte offset of the current decoder
+// position. Used together with Token, it lets a caller correlate a token
+// back to its location in the original input.
+func (dec *Decoder) InputOffset() int64 {
+	return dec.b.off
+}